@@ -0,0 +1,43 @@
+package create
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/cmdutil"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_NewCmdCreate_appFlagWiring confirms that --app is actually threaded
+// from the command layer down to the REST path used, rather than only being
+// validated and then ignored.
+func Test_NewCmdCreate_appFlagWiring(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(httpmock.REST("GET", "repos/OWNER/REPO/codespaces/secrets/public-key"), httpmock.StringResponse(testPubKeyJSON))
+	reg.Register(httpmock.REST("PUT", "repos/OWNER/REPO/codespaces/secrets/FOO"), httpmock.StatusStringResponse(204, ""))
+
+	io, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{
+		IOStreams: io,
+		HttpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: reg}, nil
+		},
+		BaseRepo: func() (ghrepo.Interface, error) {
+			return ghrepo.FromFullName("OWNER/REPO")
+		},
+	}
+
+	cmd := NewCmdCreate(f, nil)
+	cmd.SetArgs([]string{"FOO", "-b", "bar", "-a", "codespaces"})
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	_, err := cmd.ExecuteC()
+	require.NoError(t, err)
+}
@@ -0,0 +1,119 @@
+package create
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getOrgPublicKey_perApp(t *testing.T) {
+	tests := []struct {
+		app  App
+		path string
+	}{
+		{Actions, "orgs/my-org/actions/secrets/public-key"},
+		{Dependabot, "orgs/my-org/dependabot/secrets/public-key"},
+		{Codespaces, "orgs/my-org/codespaces/secrets/public-key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.app), func(t *testing.T) {
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			reg.Register(httpmock.REST("GET", tt.path), httpmock.StringResponse(testPubKeyJSON))
+
+			client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+			_, err := getOrgPublicKey(client, "github.com", "my-org", tt.app)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_getRepoPubKey_perApp(t *testing.T) {
+	tests := []struct {
+		app  App
+		path string
+	}{
+		{Actions, "repos/OWNER/REPO/actions/secrets/public-key"},
+		{Dependabot, "repos/OWNER/REPO/dependabot/secrets/public-key"},
+		{Codespaces, "repos/OWNER/REPO/codespaces/secrets/public-key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.app), func(t *testing.T) {
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			reg.Register(httpmock.REST("GET", tt.path), httpmock.StringResponse(testPubKeyJSON))
+
+			client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+			repo, err := ghrepo.FromFullName("OWNER/REPO")
+			require.NoError(t, err)
+
+			_, err = getRepoPubKey(client, repo, tt.app)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_putOrgSecret_dependabotUsesStringRepoIDs(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(
+		httpmock.GraphQL(`query MapRepositoryNames`),
+		httpmock.StringResponse(`{"data":{"myrepo":{"databaseId":1}}}`),
+	)
+	reg.Register(
+		httpmock.REST("PUT", "orgs/my-org/dependabot/secrets/FOO"),
+		httpmock.RESTPayload(204, "", func(payload map[string]interface{}) {
+			repos, ok := payload["selected_repository_ids"].([]interface{})
+			require.True(t, ok)
+			require.Len(t, repos, 1)
+			assert.Equal(t, "1", repos[0])
+		}),
+	)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+	pk, err := NewPubKey("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=", "123")
+	require.NoError(t, err)
+
+	opts := CreateOptions{
+		SecretName:      "FOO",
+		OrgName:         "my-org",
+		Visibility:      visSelected,
+		Application:     Dependabot,
+		RepositoryNames: []string{"myrepo"},
+	}
+
+	err = putOrgSecret(client, pk, "github.com", opts, "encrypted-value")
+	require.NoError(t, err)
+}
+
+func Test_putOrgSecret_selectedWithNoReposOmitsField(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(
+		httpmock.REST("PUT", "orgs/my-org/actions/secrets/FOO"),
+		httpmock.RESTPayload(204, "", func(payload map[string]interface{}) {
+			_, ok := payload["selected_repository_ids"]
+			assert.False(t, ok, "selected_repository_ids should be omitted when no repos are selected")
+		}),
+	)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+	pk, err := NewPubKey("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=", "123")
+	require.NoError(t, err)
+
+	opts := CreateOptions{
+		SecretName: "FOO",
+		OrgName:    "my-org",
+		Visibility: visSelected,
+	}
+
+	err = putOrgSecret(client, pk, "github.com", opts, "encrypted-value")
+	require.NoError(t, err)
+}
@@ -0,0 +1,131 @@
+package create
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mapRepoNameToID_batches(t *testing.T) {
+	repoNames := make([]string, 5)
+	for i := range repoNames {
+		repoNames[i] = fmt.Sprintf("repo%d", i)
+	}
+
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	// With a batch size of 2, five repos take three requests: 2, 2, 1.
+	reg.Register(httpmock.GraphQL(`query MapRepositoryNames`), httpmock.StringResponse(`{"data":{"repo0":{"databaseId":100},"repo1":{"databaseId":101}}}`))
+	reg.Register(httpmock.GraphQL(`query MapRepositoryNames`), httpmock.StringResponse(`{"data":{"repo2":{"databaseId":102},"repo3":{"databaseId":103}}}`))
+	reg.Register(httpmock.GraphQL(`query MapRepositoryNames`), httpmock.StringResponse(`{"data":{"repo4":{"databaseId":104}}}`))
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+
+	got, err := mapRepoNameToID(client, "github.com", "my-org", repoNames, Actions, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{100, 101, 102, 103, 104}, got)
+}
+
+func Test_mapRepoNameToID_notFound(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(
+		httpmock.GraphQL(`query MapRepositoryNames`),
+		httpmock.StringResponse(`{"data":{"missing":null},"errors":[{"type":"NOT_FOUND","path":["missing"],"message":"not found"}]}`),
+	)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+
+	_, err := mapRepoNameToID(client, "github.com", "my-org", []string{"missing"}, Actions, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "my-org/missing")
+}
+
+func Test_mapRepoNameToIDBatch_retriesOnRateLimit(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(
+		httpmock.GraphQL(`query MapRepositoryNames`),
+		httpmock.StringResponse(`{"data":null,"errors":[{"type":"RATE_LIMITED","message":"rate limited"}]}`),
+	)
+	reg.Register(
+		httpmock.REST("GET", "rate_limit"),
+		httpmock.StringResponse(`{"resources":{"graphql":{"reset":0}}}`),
+	)
+	reg.Register(
+		httpmock.GraphQL(`query MapRepositoryNames`),
+		httpmock.StringResponse(`{"data":{"repo0":{"databaseId":100}}}`),
+	)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+
+	got, err := mapRepoNameToIDBatch(client, "github.com", "my-org", []string{"repo0"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"repo0": 100}, got)
+}
+
+func Test_mapRepoNameToIDBatch_retriesOnSecondaryRateLimit(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	// A secondary rate limit surfaces as a plain HTTP 403, not a GraphQL
+	// RATE_LIMITED error, and shouldn't consult the REST rate_limit endpoint.
+	reg.Register(
+		httpmock.GraphQL(`query MapRepositoryNames`),
+		jsonStatusResponse(http.StatusForbidden, `{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`),
+	)
+	reg.Register(
+		httpmock.GraphQL(`query MapRepositoryNames`),
+		httpmock.StringResponse(`{"data":{"repo0":{"databaseId":100}}}`),
+	)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+
+	got, err := mapRepoNameToIDBatch(client, "github.com", "my-org", []string{"repo0"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"repo0": 100}, got)
+}
+
+func Test_mapRepoNameToIDBatch_permission403FailsFast(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	// An ordinary permission error also comes back as HTTP 403, but without
+	// rate-limit wording, so it must surface immediately rather than being
+	// retried for several seconds.
+	reg.Register(
+		httpmock.GraphQL(`query MapRepositoryNames`),
+		jsonStatusResponse(http.StatusForbidden, `{"message":"Resource not accessible by integration"}`),
+	)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+
+	_, err := mapRepoNameToIDBatch(client, "github.com", "my-org", []string{"repo0"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Resource not accessible by integration")
+}
+
+// jsonStatusResponse is like httpmock.StatusStringResponse but also sets a
+// JSON Content-Type, which api.HandleHTTPError requires in order to parse
+// the response body into HTTPError.Message instead of falling back to the
+// raw HTTP status text.
+func jsonStatusResponse(status int, body string) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Request:    req,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+}
@@ -0,0 +1,208 @@
+package create
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/cmdutil"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/nacl/box"
+)
+
+const (
+	visAll      = "all"
+	visPrivate  = "private"
+	visSelected = "selected"
+)
+
+type CreateOptions struct {
+	HttpClient func() (*http.Client, error)
+	IO         *iostreams.IOStreams
+	BaseRepo   func() (ghrepo.Interface, error)
+
+	RandomOverride func() io.Reader
+
+	SecretName      string
+	OrgName         string
+	EnvName         string
+	Body            string
+	Visibility      string
+	Application     App
+	RepositoryNames []string
+	RepoBatchSize   int
+
+	EnvFile string
+	DryRun  bool
+}
+
+func NewCmdCreate(f *cmdutil.Factory, runF func(*CreateOptions) error) *cobra.Command {
+	opts := &CreateOptions{
+		IO:         f.IOStreams,
+		HttpClient: f.HttpClient,
+		BaseRepo:   f.BaseRepo,
+	}
+
+	var app string
+
+	cmd := &cobra.Command{
+		Use:   "set <secret-name>",
+		Short: "Create or update secrets",
+		Long: heredoc.Doc(`
+			Set a value for a secret on one of the following levels:
+			- repository (default): available to GitHub Actions runs, Dependabot, or Codespaces in a repository
+			- environment: available to GitHub Actions runs for a deployment environment in a repository
+			- organization: available to GitHub Actions runs, Dependabot, or Codespaces within an organization
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opts.SecretName = args[0]
+			}
+			if opts.SecretName == "" && opts.EnvFile == "" {
+				return cmdutil.FlagErrorf("must pass a secret name or `-f` to import secrets from a file")
+			}
+
+			if opts.SecretName != "" && opts.EnvFile != "" {
+				return cmdutil.FlagErrorf("cannot pass a secret name with `-f`/`--env-file`; import reads secret names from the file")
+			}
+
+			if opts.OrgName != "" && opts.EnvName != "" {
+				return cmdutil.FlagErrorf("specify only one of `--org` or `--env`")
+			}
+
+			if opts.EnvName != "" && opts.EnvFile != "" {
+				return cmdutil.FlagErrorf("`--env` is not supported with `-f`/`--env-file`; import targets the repository or `--org` scope only")
+			}
+
+			opts.Application = App(app)
+			switch opts.Application {
+			case Actions, Codespaces, Dependabot:
+			default:
+				return cmdutil.FlagErrorf("invalid value for --app: %q", app)
+			}
+
+			if opts.EnvName != "" && opts.Application != Actions {
+				return cmdutil.FlagErrorf("`--env` is only supported for the `actions` app")
+			}
+
+			if opts.Visibility != visAll && opts.Visibility != visPrivate && opts.Visibility != visSelected {
+				return cmdutil.FlagErrorf("invalid value for --visibility: %q", opts.Visibility)
+			}
+
+			if opts.Visibility != visSelected && len(opts.RepositoryNames) > 0 {
+				return cmdutil.FlagErrorf("`--repos` is only supported with `--visibility=selected`")
+			}
+
+			if opts.EnvFile == "" {
+				body, err := getBody(opts)
+				if err != nil {
+					return fmt.Errorf("failed to get secret body: %w", err)
+				}
+				opts.Body = body
+			}
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return RunCreate(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Body, "body", "b", "", "The value for the secret (reads from standard input if not specified)")
+	cmd.Flags().StringVarP(&opts.Visibility, "visibility", "v", visPrivate, "Set visibility for an organization secret: `all`, `private`, or `selected`")
+	cmd.Flags().StringSliceVarP(&opts.RepositoryNames, "repos", "r", []string{}, "List of repositories that can access an organization secret")
+	cmd.Flags().StringVarP(&opts.OrgName, "org", "o", "", "Set a secret for an organization")
+	cmd.Flags().StringVarP(&opts.EnvName, "env", "e", "", "Set a secret for an environment")
+	cmd.Flags().StringVarP(&app, "app", "a", string(Actions), "Set the application for a secret: `actions`, `codespaces`, or `dependabot`")
+	cmd.Flags().StringVarP(&opts.EnvFile, "env-file", "f", "", "Load secrets in bulk from a `.env`-formatted file")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the secrets that would be set, without setting them")
+	cmd.Flags().IntVar(&opts.RepoBatchSize, "batch-size", defaultRepoNameBatchSize, "Number of selected repository names resolved per GraphQL request")
+
+	return cmd
+}
+
+func getBody(opts *CreateOptions) (string, error) {
+	if opts.Body != "" {
+		return opts.Body, nil
+	}
+
+	if opts.IO.CanPrompt() {
+		return "", fmt.Errorf("must pass `--body` or pipe in a value for the secret")
+	}
+
+	body, err := ioutil.ReadAll(opts.IO.In)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from standard input: %w", err)
+	}
+
+	return strings.TrimRight(string(body), "\r\n"), nil
+}
+
+func RunCreate(opts *CreateOptions) error {
+	httpClient, err := opts.HttpClient()
+	if err != nil {
+		return fmt.Errorf("could not create http client: %w", err)
+	}
+	client := api.NewClientFromHTTP(httpClient)
+
+	baseRepo, err := opts.BaseRepo()
+	if err != nil {
+		return fmt.Errorf("could not determine base repo: %w", err)
+	}
+
+	if opts.EnvFile != "" {
+		return runImport(client, baseRepo, opts)
+	}
+
+	var pk *PubKey
+	switch {
+	case opts.EnvName != "":
+		pk, err = getEnvPubKey(client, baseRepo, opts.EnvName)
+	case opts.OrgName != "":
+		pk, err = getOrgPublicKey(client, baseRepo.RepoHost(), opts.OrgName, opts.Application)
+	default:
+		pk, err = getRepoPubKey(client, baseRepo, opts.Application)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch public key: %w", err)
+	}
+
+	eValue, err := sealSecret(opts, pk, opts.Body)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case opts.EnvName != "":
+		return putEnvSecret(client, pk, baseRepo, opts.EnvName, opts.SecretName, eValue)
+	case opts.OrgName != "":
+		return putOrgSecret(client, pk, baseRepo.RepoHost(), *opts, eValue)
+	default:
+		return putRepoSecret(client, pk, baseRepo, opts.Application, opts.SecretName, eValue)
+	}
+}
+
+// sealSecret encrypts value for the recipient named by pk using a NaCl
+// sealed box, the same scheme GitHub's secrets API expects for encrypted_value.
+func sealSecret(opts *CreateOptions, pk *PubKey, value string) (string, error) {
+	randReader := rand.Reader
+	if opts.RandomOverride != nil {
+		randReader = opts.RandomOverride()
+	}
+
+	eValue, err := box.SealAnonymous(nil, []byte(value), &pk.Key, randReader)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt body: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(eValue), nil
+}
@@ -0,0 +1,114 @@
+package create
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/cmdutil"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewCmdCreate_flagValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		argv    []string
+		wantErr string
+	}{
+		{
+			name:    "org and env are mutually exclusive",
+			argv:    []string{"FOO", "-b", "bar", "-o", "my-org", "-e", "staging"},
+			wantErr: "specify only one of `--org` or `--env`",
+		},
+		{
+			name:    "env and env-file are mutually exclusive",
+			argv:    []string{"-f", "secrets.env", "-e", "staging"},
+			wantErr: "`--env` is not supported with `-f`/`--env-file`; import targets the repository or `--org` scope only",
+		},
+		{
+			name:    "secret name and env-file are mutually exclusive",
+			argv:    []string{"FOO", "-f", "secrets.env"},
+			wantErr: "cannot pass a secret name with `-f`/`--env-file`; import reads secret names from the file",
+		},
+		{
+			name:    "invalid app",
+			argv:    []string{"FOO", "-b", "bar", "-a", "packages"},
+			wantErr: `invalid value for --app: "packages"`,
+		},
+		{
+			name:    "env restricted to actions app",
+			argv:    []string{"FOO", "-b", "bar", "-e", "staging", "-a", "dependabot"},
+			wantErr: "`--env` is only supported for the `actions` app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			io, _, _, _ := iostreams.Test()
+			f := &cmdutil.Factory{IOStreams: io}
+
+			cmd := NewCmdCreate(f, func(opts *CreateOptions) error {
+				return nil
+			})
+			cmd.SetArgs(tt.argv)
+			cmd.SetIn(&bytes.Buffer{})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+
+			_, err := cmd.ExecuteC()
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+		})
+	}
+}
+
+func Test_NewCmdCreate_batchSizeFlag(t *testing.T) {
+	io, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{IOStreams: io}
+
+	var gotOpts *CreateOptions
+	cmd := NewCmdCreate(f, func(opts *CreateOptions) error {
+		gotOpts = opts
+		return nil
+	})
+	cmd.SetArgs([]string{"FOO", "-b", "bar", "-o", "my-org", "-v", "selected", "-r", "repo-a", "--batch-size", "7"})
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	_, err := cmd.ExecuteC()
+	require.NoError(t, err)
+	require.NotNil(t, gotOpts)
+	assert.Equal(t, 7, gotOpts.RepoBatchSize)
+}
+
+func Test_NewCmdCreate_runsThroughRunE(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(httpmock.REST("GET", "repos/OWNER/REPO/actions/secrets/public-key"), httpmock.StringResponse(testPubKeyJSON))
+	reg.Register(httpmock.REST("PUT", "repos/OWNER/REPO/actions/secrets/FOO"), httpmock.StatusStringResponse(204, ""))
+
+	io, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{
+		IOStreams: io,
+		HttpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: reg}, nil
+		},
+		BaseRepo: func() (ghrepo.Interface, error) {
+			return ghrepo.FromFullName("OWNER/REPO")
+		},
+	}
+
+	cmd := NewCmdCreate(f, nil)
+	cmd.SetArgs([]string{"FOO", "-b", "bar"})
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	_, err := cmd.ExecuteC()
+	require.NoError(t, err)
+}
@@ -0,0 +1,66 @@
+package create
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dotenvEntry is one KEY=value pair parsed from a secrets file.
+type dotenvEntry struct {
+	Key   string
+	Value string
+}
+
+// parseDotenv parses KEY=value pairs out of r, skipping blank lines and
+// lines starting with '#', and stripping a single layer of surrounding
+// single or double quotes from the value.
+func parseDotenv(r io.Reader) ([]dotenvEntry, error) {
+	var entries []dotenvEntry
+
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := splitDotenvLine(line)
+		if !ok {
+			return nil, fmt.Errorf("error parsing secrets file on line %d: %q", lineNum, line)
+		}
+
+		entries = append(entries, dotenvEntry{Key: key, Value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading secrets file: %w", err)
+	}
+
+	return entries, nil
+}
+
+func splitDotenvLine(line string) (key, value string, ok bool) {
+	idx := strings.IndexByte(line, '=')
+	if idx < 0 {
+		return "", "", false
+	}
+
+	key = strings.TrimSpace(line[:idx])
+	if key == "" {
+		return "", "", false
+	}
+
+	value = unquoteDotenvValue(strings.TrimSpace(line[idx+1:]))
+	return key, value, true
+}
+
+func unquoteDotenvValue(s string) string {
+	if len(s) < 2 {
+		return s
+	}
+	if (s[0] == '"' && s[len(s)-1] == '"') || (s[0] == '\'' && s[len(s)-1] == '\'') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
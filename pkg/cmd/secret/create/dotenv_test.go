@@ -0,0 +1,67 @@
+package create
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []dotenvEntry
+		wantErr bool
+	}{
+		{
+			name:  "basic",
+			input: "FOO=bar\nBAZ=qux\n",
+			want: []dotenvEntry{
+				{Key: "FOO", Value: "bar"},
+				{Key: "BAZ", Value: "qux"},
+			},
+		},
+		{
+			name:  "comments and blank lines",
+			input: "# a comment\n\nFOO=bar\n  # indented comment\nBAZ=qux\n",
+			want: []dotenvEntry{
+				{Key: "FOO", Value: "bar"},
+				{Key: "BAZ", Value: "qux"},
+			},
+		},
+		{
+			name:  "quoted values",
+			input: `FOO="bar baz"` + "\n" + `BAZ='qux quux'` + "\n",
+			want: []dotenvEntry{
+				{Key: "FOO", Value: "bar baz"},
+				{Key: "BAZ", Value: "qux quux"},
+			},
+		},
+		{
+			name:  "value containing an equals sign",
+			input: "FOO=bar=baz\n",
+			want: []dotenvEntry{
+				{Key: "FOO", Value: "bar=baz"},
+			},
+		},
+		{
+			name:    "missing equals sign",
+			input:   "NOT_A_VALID_LINE\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDotenv(strings.NewReader(tt.input))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
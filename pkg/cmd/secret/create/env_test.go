@@ -0,0 +1,50 @@
+package create
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPubKeyJSON = `{"key_id":"123","key":"AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="}`
+
+func Test_getEnvPubKey(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(
+		httpmock.REST("GET", "repos/OWNER/REPO/environments/staging/secrets/public-key"),
+		httpmock.StringResponse(testPubKeyJSON),
+	)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+	repo, err := ghrepo.FromFullName("OWNER/REPO")
+	require.NoError(t, err)
+
+	pk, err := getEnvPubKey(client, repo, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, "123", pk.ID)
+}
+
+func Test_putEnvSecret(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(
+		httpmock.REST("PUT", "repos/OWNER/REPO/environments/staging/secrets/FOO"),
+		httpmock.StatusStringResponse(204, ""),
+	)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+	repo, err := ghrepo.FromFullName("OWNER/REPO")
+	require.NoError(t, err)
+
+	pk, err := NewPubKey("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=", "123")
+	require.NoError(t, err)
+
+	err = putEnvSecret(client, pk, repo, "staging", "FOO", "encrypted-value")
+	require.NoError(t, err)
+}
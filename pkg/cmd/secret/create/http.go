@@ -4,20 +4,57 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cli/cli/api"
 	"github.com/cli/cli/internal/ghrepo"
 )
 
-func getOrgPublicKey(client *api.Client, host, orgName string) (*PubKey, error) {
-	return getPubKey(client, host, fmt.Sprintf("orgs/%s/actions/secrets/public-key", orgName))
+// defaultRepoNameBatchSize is the default number of repository names resolved
+// per GraphQL request. Aliasing more than this in a single query risks
+// tripping GitHub's node/complexity limits for orgs with large selected-repo
+// lists. Callers that need a different size (e.g. tests) can override it by
+// passing batchSize to mapRepoNameToID; a batchSize of 0 means "use the
+// default".
+const defaultRepoNameBatchSize = 50
+
+// repoNameLookupRetries bounds how many times a single batch is retried after
+// a rate-limited response before giving up.
+const repoNameLookupRetries = 3
+
+// App is the secrets namespace a secret belongs to. GitHub exposes largely
+// parallel REST APIs for each of these under orgs/repos.
+type App string
+
+const (
+	Actions    App = "actions"
+	Codespaces App = "codespaces"
+	Dependabot App = "dependabot"
+)
+
+// usesStringRepoIDs reports whether the given app's selected-repository
+// payload is addressed by string repository IDs rather than ints.
+func (a App) usesStringRepoIDs() bool {
+	return a == Dependabot
 }
 
-func getRepoPubKey(client *api.Client, repo ghrepo.Interface) (*PubKey, error) {
-	return getPubKey(client, repo.RepoHost(), fmt.Sprintf("repos/%s/actions/secrets/public-key",
-		ghrepo.FullName(repo)))
+func getOrgPublicKey(client *api.Client, host, orgName string, app App) (*PubKey, error) {
+	return getPubKey(client, host, fmt.Sprintf("orgs/%s/%s/secrets/public-key", orgName, app))
+}
+
+func getRepoPubKey(client *api.Client, repo ghrepo.Interface, app App) (*PubKey, error) {
+	return getPubKey(client, repo.RepoHost(), fmt.Sprintf("repos/%s/%s/secrets/public-key",
+		ghrepo.FullName(repo), app))
+}
+
+func getEnvPubKey(client *api.Client, repo ghrepo.Interface, envName string) (*PubKey, error) {
+	return getPubKey(client, repo.RepoHost(), fmt.Sprintf("repos/%s/environments/%s/secrets/public-key",
+		ghrepo.FullName(repo), envName))
 }
 
 type PubKey struct {
@@ -64,43 +101,89 @@ func getPubKey(client *api.Client, host, path string) (*PubKey, error) {
 }
 
 type SecretPayload struct {
-	EncryptedValue string `json:"encrypted_value"`
-	Visibility     string `json:"visibility,omitempty"`
-	Repositories   []int  `json:"selected_repository_ids,omitempty"`
-	KeyID          string `json:"key_id"`
+	EncryptedValue string      `json:"encrypted_value"`
+	Visibility     string      `json:"visibility,omitempty"`
+	Repositories   interface{} `json:"selected_repository_ids,omitempty"`
+	KeyID          string      `json:"key_id"`
 }
 
 func putOrgSecret(client *api.Client, pk *PubKey, host string, opts CreateOptions, eValue string) error {
-	secretName := opts.SecretName
-	orgName := opts.OrgName
-	visibility := opts.Visibility
-
-	var repositoryIDs []int
+	var repositoryIDs interface{}
 	var err error
-	if orgName != "" && visibility == visSelected {
-		repositoryIDs, err = mapRepoNameToID(client, host, orgName, opts.RepositoryNames)
+	if opts.OrgName != "" && opts.Visibility == visSelected {
+		repositoryIDs, err = mapRepoNameToID(client, host, opts.OrgName, opts.RepositoryNames, opts.Application, opts.RepoBatchSize)
 		if err != nil {
 			return fmt.Errorf("failed to look up IDs for repositories %v: %w", opts.RepositoryNames, err)
 		}
 	}
 
+	return putOrgSecretWithRepoIDs(client, pk, host, opts.OrgName, opts.SecretName, opts.Visibility, opts.Application, repositoryIDs, eValue)
+}
+
+// putOrgSecretWithRepoIDs is putOrgSecret for a caller that has already
+// resolved the selected repositories to IDs, so it can be reused across many
+// secrets (e.g. a bulk import) without re-resolving repositoryIDs each time.
+func putOrgSecretWithRepoIDs(client *api.Client, pk *PubKey, host, orgName, secretName, visibility string, app App, repositoryIDs interface{}, eValue string) error {
 	payload := SecretPayload{
 		EncryptedValue: eValue,
 		KeyID:          pk.ID,
 		Repositories:   repositoryIDs,
 		Visibility:     visibility,
 	}
-	path := fmt.Sprintf("orgs/%s/actions/secrets/%s", orgName, secretName)
+	path := fmt.Sprintf("orgs/%s/%s/secrets/%s", orgName, app, secretName)
 
 	return putSecret(client, host, path, payload)
 }
 
-func putRepoSecret(client *api.Client, pk *PubKey, repo ghrepo.Interface, secretName, eValue string) error {
+func putRepoSecret(client *api.Client, pk *PubKey, repo ghrepo.Interface, app App, secretName, eValue string) error {
 	payload := SecretPayload{
 		EncryptedValue: eValue,
 		KeyID:          pk.ID,
 	}
-	path := fmt.Sprintf("repos/%s/actions/secrets/%s", ghrepo.FullName(repo), secretName)
+	path := fmt.Sprintf("repos/%s/%s/secrets/%s", ghrepo.FullName(repo), app, secretName)
+	return putSecret(client, repo.RepoHost(), path, payload)
+}
+
+// listOrgSecretRepos returns the database IDs of the repositories currently
+// selected for the given org secret.
+func listOrgSecretRepos(client *api.Client, host, orgName, secretName string) ([]int, error) {
+	result := struct {
+		Repositories []struct {
+			ID int `json:"id"`
+		} `json:"repositories"`
+	}{}
+
+	path := fmt.Sprintf("orgs/%s/actions/secrets/%s/repositories", orgName, secretName)
+	if err := client.REST(host, "GET", path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to look up repositories selected for %s: %w", secretName, err)
+	}
+
+	ids := make([]int, len(result.Repositories))
+	for i, r := range result.Repositories {
+		ids[i] = r.ID
+	}
+
+	return ids, nil
+}
+
+// putOrgSecretRepo grants repoID access to the selected org secret.
+func putOrgSecretRepo(client *api.Client, host, orgName, secretName string, repoID int) error {
+	path := fmt.Sprintf("orgs/%s/actions/secrets/%s/repositories/%d", orgName, secretName, repoID)
+	return client.REST(host, "PUT", path, nil, nil)
+}
+
+// deleteOrgSecretRepo revokes repoID's access to the selected org secret.
+func deleteOrgSecretRepo(client *api.Client, host, orgName, secretName string, repoID int) error {
+	path := fmt.Sprintf("orgs/%s/actions/secrets/%s/repositories/%d", orgName, secretName, repoID)
+	return client.REST(host, "DELETE", path, nil, nil)
+}
+
+func putEnvSecret(client *api.Client, pk *PubKey, repo ghrepo.Interface, envName, secretName, eValue string) error {
+	payload := SecretPayload{
+		EncryptedValue: eValue,
+		KeyID:          pk.ID,
+	}
+	path := fmt.Sprintf("repos/%s/environments/%s/secrets/%s", ghrepo.FullName(repo), envName, secretName)
 	return putSecret(client, repo.RepoHost(), path, payload)
 }
 
@@ -114,7 +197,63 @@ func putSecret(client *api.Client, host, path string, payload SecretPayload) err
 	return client.REST(host, "PUT", path, requestBody, nil)
 }
 
-func mapRepoNameToID(client *api.Client, host, orgName string, repositoryNames []string) ([]int, error) {
+// mapRepoNameToID resolves repositoryNames to their database IDs. Most apps
+// (Actions, Codespaces) want these as ints, but Dependabot org secrets address
+// selected repositories by their string ID, so the return type follows app.
+//
+// repositoryNames is resolved in batches of batchSize (defaultRepoNameBatchSize
+// if batchSize is 0) so that orgs with large selected-repo lists don't blow
+// past GitHub's GraphQL node/complexity limits with a single giant aliased
+// query.
+func mapRepoNameToID(client *api.Client, host, orgName string, repositoryNames []string, app App, batchSize int) (interface{}, error) {
+	// Return a true nil interface, not a typed nil slice, so that callers
+	// marshaling this into SecretPayload.Repositories get `omitempty`'s
+	// intended effect instead of an empty "selected_repository_ids":[].
+	if len(repositoryNames) == 0 {
+		return nil, nil
+	}
+
+	if batchSize <= 0 {
+		batchSize = defaultRepoNameBatchSize
+	}
+
+	ids := make(map[string]int, len(repositoryNames))
+
+	for start := 0; start < len(repositoryNames); start += batchSize {
+		end := start + batchSize
+		if end > len(repositoryNames) {
+			end = len(repositoryNames)
+		}
+		batch := repositoryNames[start:end]
+
+		batchResult, err := mapRepoNameToIDBatch(client, host, orgName, batch)
+		if err != nil {
+			return nil, err
+		}
+		for name, id := range batchResult {
+			ids[name] = id
+		}
+	}
+
+	if app.usesStringRepoIDs() {
+		result := make([]string, 0, len(repositoryNames))
+		for _, repoName := range repositoryNames {
+			result = append(result, strconv.Itoa(ids[repoName]))
+		}
+		return result, nil
+	}
+
+	result := make([]int, 0, len(repositoryNames))
+	for _, repoName := range repositoryNames {
+		result = append(result, ids[repoName])
+	}
+
+	return result, nil
+}
+
+// mapRepoNameToIDBatch resolves a single batch of repository names, retrying
+// on rate limit errors with exponential backoff.
+func mapRepoNameToIDBatch(client *api.Client, host, orgName string, repositoryNames []string) (map[string]int, error) {
 	queries := make([]string, 0, len(repositoryNames))
 	for _, repoName := range repositoryNames {
 		queries = append(queries, fmt.Sprintf(`
@@ -130,25 +269,108 @@ func mapRepoNameToID(client *api.Client, host, orgName string, repositoryNames [
 		DatabaseID int `json:"databaseId"`
 	})
 
-	err := client.GraphQL(host, query, nil, &graphqlResult)
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = client.GraphQL(host, query, nil, &graphqlResult)
 
-	gqlErr, isGqlErr := err.(*api.GraphQLErrorResponse)
-	if isGqlErr {
-		for _, ge := range gqlErr.Errors {
-			if ge.Type == "NOT_FOUND" {
-				return nil, fmt.Errorf("could not find %s/%s", orgName, ge.Path[0])
+		if gqlErr, ok := err.(*api.GraphQLErrorResponse); ok {
+			for _, ge := range gqlErr.Errors {
+				if ge.Type == "NOT_FOUND" {
+					return nil, fmt.Errorf("could not find %s/%s", orgName, ge.Path[0])
+				}
 			}
 		}
+
+		if isRateLimited(err) && attempt < repoNameLookupRetries {
+			time.Sleep(rateLimitBackoff(client, host, err, attempt))
+			continue
+		}
+		break
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to look up repositories: %w", err)
 	}
 
-	result := make([]int, 0, len(repositoryNames))
-
+	result := make(map[string]int, len(repositoryNames))
 	for _, repoName := range repositoryNames {
-		result = append(result, graphqlResult[repoName].DatabaseID)
+		result[repoName] = graphqlResult[repoName].DatabaseID
 	}
 
 	return result, nil
 }
+
+// isRateLimited reports whether err represents either a primary GraphQL rate
+// limit (a RATE_LIMITED error in the response body) or a secondary rate
+// limit, which GitHub instead reports as a plain HTTP 403/429. A 429 is
+// unambiguous, but a 403 also covers ordinary permission errors (bad token
+// scope, not an org member, app disabled for secrets), so those are only
+// treated as rate limiting when the response body says so.
+func isRateLimited(err error) bool {
+	if gqlErr, ok := err.(*api.GraphQLErrorResponse); ok {
+		for _, ge := range gqlErr.Errors {
+			if ge.Type == "RATE_LIMITED" {
+				return true
+			}
+		}
+	}
+
+	var httpErr api.HTTPError
+	if errors.As(err, &httpErr) {
+		if httpErr.StatusCode == http.StatusTooManyRequests {
+			return true
+		}
+		if httpErr.StatusCode == http.StatusForbidden && strings.Contains(strings.ToLower(httpErr.Message), "rate limit") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rateLimitBackoff returns the delay before retrying attempt (0-indexed).
+// For a primary GraphQL rate limit it waits until the GraphQL resource's reset
+// time as reported by the REST rate_limit endpoint (the closest equivalent
+// the API client exposes to the X-RateLimit-Reset header, since
+// client.GraphQL doesn't surface response headers) or for the exponential
+// backoff delay, whichever is longer; the exponential delay alone is used if
+// that lookup fails or reports no future reset. A secondary/abuse-detection
+// limit is reported as a plain HTTP 403/429 with no relationship to the
+// GraphQL resource's reset time, so that case always uses the exponential
+// fallback instead.
+func rateLimitBackoff(client *api.Client, host string, err error, attempt int) time.Duration {
+	fallback := time.Duration(1<<uint(attempt)) * time.Second
+
+	var httpErr api.HTTPError
+	if errors.As(err, &httpErr) {
+		return fallback
+	}
+
+	reset, resetErr := graphqlRateLimitReset(client, host)
+	if resetErr != nil || reset <= 0 {
+		return fallback
+	}
+
+	if reset > fallback {
+		return reset
+	}
+	return fallback
+}
+
+// graphqlRateLimitReset returns how long to wait until GitHub's GraphQL rate
+// limit resets, per GET rate_limit.
+func graphqlRateLimitReset(client *api.Client, host string) (time.Duration, error) {
+	var result struct {
+		Resources struct {
+			Graphql struct {
+				Reset int64 `json:"reset"`
+			} `json:"graphql"`
+		} `json:"resources"`
+	}
+
+	if err := client.REST(host, "GET", "rate_limit", nil, &result); err != nil {
+		return 0, err
+	}
+
+	resetAt := time.Unix(result.Resources.Graphql.Reset, 0)
+	return time.Until(resetAt), nil
+}
@@ -0,0 +1,169 @@
+package create
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/ghrepo"
+)
+
+// importConcurrency bounds how many secrets are uploaded at once when
+// importing from a file.
+const importConcurrency = 8
+
+// ImportResult records the outcome of importing a single key.
+type ImportResult struct {
+	Key         string
+	Overwritten bool
+	Err         error
+}
+
+// putSecretFunc uploads a single already-sealed secret value for key and
+// reports any error. It closes over the destination scope (org or repo) so
+// ImportSecrets stays agnostic to where the secrets are going.
+type putSecretFunc func(key, sealedValue string) error
+
+// ImportSecrets seals each entry's value with encrypt and uploads the
+// results concurrently via put, bounded to importConcurrency in flight at
+// once. Every entry is attempted regardless of earlier failures, and the
+// per-key outcome is returned once all uploads finish. existingNames marks
+// which keys are being overwritten rather than created. When dryRun is true,
+// put is never called.
+func ImportSecrets(entries []dotenvEntry, existingNames map[string]bool, encrypt func(string) (string, error), put putSecretFunc, dryRun bool) []ImportResult {
+	results := make([]ImportResult, len(entries))
+
+	sem := make(chan struct{}, importConcurrency)
+	var wg sync.WaitGroup
+
+	for i, entry := range entries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, entry dotenvEntry) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = importOne(entry, existingNames[entry.Key], encrypt, put, dryRun)
+		}(i, entry)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func importOne(entry dotenvEntry, overwritten bool, encrypt func(string) (string, error), put putSecretFunc, dryRun bool) ImportResult {
+	if dryRun {
+		return ImportResult{Key: entry.Key, Overwritten: overwritten}
+	}
+
+	sealed, err := encrypt(entry.Value)
+	if err != nil {
+		return ImportResult{Key: entry.Key, Overwritten: overwritten, Err: err}
+	}
+
+	if err := put(entry.Key, sealed); err != nil {
+		return ImportResult{Key: entry.Key, Overwritten: overwritten, Err: err}
+	}
+
+	return ImportResult{Key: entry.Key, Overwritten: overwritten}
+}
+
+// runImport drives the `-f`/`--env-file` import path: it parses the dotenv
+// file, fetches the target public key once, lists existing secrets to tell
+// creates from overwrites, then fans the uploads out through ImportSecrets.
+func runImport(client *api.Client, baseRepo ghrepo.Interface, opts *CreateOptions) error {
+	f, err := os.Open(opts.EnvFile)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", opts.EnvFile, err)
+	}
+	defer f.Close()
+
+	entries, err := parseDotenv(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", opts.EnvFile, err)
+	}
+
+	var pk *PubKey
+	var existingNames map[string]bool
+	var put putSecretFunc
+
+	switch {
+	case opts.OrgName != "":
+		pk, err = getOrgPublicKey(client, baseRepo.RepoHost(), opts.OrgName, opts.Application)
+		if err == nil {
+			existingNames, err = listSecretNames(client, baseRepo.RepoHost(), fmt.Sprintf("orgs/%s/%s/secrets", opts.OrgName, opts.Application))
+		}
+
+		var repositoryIDs interface{}
+		if err == nil && opts.Visibility == visSelected {
+			repositoryIDs, err = mapRepoNameToID(client, baseRepo.RepoHost(), opts.OrgName, opts.RepositoryNames, opts.Application, opts.RepoBatchSize)
+		}
+
+		put = func(key, sealedValue string) error {
+			return putOrgSecretWithRepoIDs(client, pk, baseRepo.RepoHost(), opts.OrgName, key, opts.Visibility, opts.Application, repositoryIDs, sealedValue)
+		}
+	default:
+		pk, err = getRepoPubKey(client, baseRepo, opts.Application)
+		if err == nil {
+			existingNames, err = listSecretNames(client, baseRepo.RepoHost(), fmt.Sprintf("repos/%s/%s/secrets", ghrepo.FullName(baseRepo), opts.Application))
+		}
+		put = func(key, sealedValue string) error {
+			return putRepoSecret(client, pk, baseRepo, opts.Application, key, sealedValue)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to prepare import: %w", err)
+	}
+
+	encrypt := func(value string) (string, error) {
+		return sealSecret(opts, pk, value)
+	}
+
+	results := ImportSecrets(entries, existingNames, encrypt, put, opts.DryRun)
+
+	var failed []ImportResult
+	for _, r := range results {
+		verb := "Set"
+		if opts.DryRun {
+			verb = "Would set"
+		}
+		if r.Overwritten {
+			verb += " (overwriting)"
+		}
+		if r.Err != nil {
+			failed = append(failed, r)
+			fmt.Fprintf(opts.IO.ErrOut, "X Failed to set %s: %s\n", r.Key, r.Err)
+			continue
+		}
+		fmt.Fprintf(opts.IO.Out, "%s %s\n", verb, r.Key)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to set %d of %d secrets", len(failed), len(results))
+	}
+
+	return nil
+}
+
+// listSecretNames returns the set of secret names that already exist at
+// path (an orgs/.../secrets or repos/.../secrets listing endpoint).
+func listSecretNames(client *api.Client, host, path string) (map[string]bool, error) {
+	result := struct {
+		Secrets []struct {
+			Name string `json:"name"`
+		} `json:"secrets"`
+	}{}
+
+	if err := client.REST(host, "GET", path, nil, &result); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(result.Secrets))
+	for _, s := range result.Secrets {
+		names[s.Name] = true
+	}
+
+	return names, nil
+}
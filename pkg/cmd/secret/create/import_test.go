@@ -0,0 +1,194 @@
+package create
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/cmdutil"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportSecrets(t *testing.T) {
+	entries := []dotenvEntry{
+		{Key: "FOO", Value: "1"},
+		{Key: "BAR", Value: "2"},
+		{Key: "BAZ", Value: "3"},
+	}
+	existing := map[string]bool{"BAR": true}
+
+	var mu sync.Mutex
+	var put []string
+	encrypt := func(v string) (string, error) { return "sealed:" + v, nil }
+	putFn := func(key, sealedValue string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		put = append(put, fmt.Sprintf("%s=%s", key, sealedValue))
+		return nil
+	}
+
+	results := ImportSecrets(entries, existing, encrypt, putFn, false)
+
+	sort.Strings(put)
+	assert.Equal(t, []string{"BAR=sealed:2", "BAZ=sealed:3", "FOO=sealed:1"}, put)
+
+	overwritten := map[string]bool{}
+	for _, r := range results {
+		require.NoError(t, r.Err)
+		overwritten[r.Key] = r.Overwritten
+	}
+	assert.Equal(t, map[string]bool{"FOO": false, "BAR": true, "BAZ": false}, overwritten)
+}
+
+func TestImportSecrets_dryRunSendsNothing(t *testing.T) {
+	entries := []dotenvEntry{{Key: "FOO", Value: "1"}}
+
+	putCalled := false
+	encryptCalled := false
+	results := ImportSecrets(entries, nil,
+		func(v string) (string, error) { encryptCalled = true; return v, nil },
+		func(key, sealedValue string) error { putCalled = true; return nil },
+		true,
+	)
+
+	assert.False(t, putCalled)
+	assert.False(t, encryptCalled)
+	require.Len(t, results, 1)
+	assert.Equal(t, "FOO", results[0].Key)
+	assert.NoError(t, results[0].Err)
+}
+
+func TestImportSecrets_collectsFailuresWithoutStopping(t *testing.T) {
+	entries := []dotenvEntry{
+		{Key: "FOO", Value: "1"},
+		{Key: "BAD", Value: "2"},
+		{Key: "BAR", Value: "3"},
+	}
+
+	results := ImportSecrets(entries, nil,
+		func(v string) (string, error) { return v, nil },
+		func(key, sealedValue string) error {
+			if key == "BAD" {
+				return fmt.Errorf("boom")
+			}
+			return nil
+		},
+		false,
+	)
+
+	var failed, succeeded int
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			assert.Equal(t, "BAD", r.Key)
+			continue
+		}
+		succeeded++
+	}
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, 2, succeeded)
+}
+
+func Test_NewCmdCreate_envFileRejectsEnv(t *testing.T) {
+	f := newEnvFile(t, "FOO=bar\n")
+	defer os.Remove(f)
+
+	io, _, _, _ := iostreams.Test()
+	factory := &cmdutil.Factory{IOStreams: io}
+
+	cmd := NewCmdCreate(factory, func(opts *CreateOptions) error { return nil })
+	cmd.SetArgs([]string{"-f", f, "-e", "staging"})
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	_, err := cmd.ExecuteC()
+	require.Error(t, err)
+	assert.Equal(t, "`--env` is not supported with `-f`/`--env-file`; import targets the repository or `--org` scope only", err.Error())
+}
+
+func Test_NewCmdCreate_runsImportThroughRunE(t *testing.T) {
+	f := newEnvFile(t, "FOO=bar\n")
+	defer os.Remove(f)
+
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(httpmock.REST("GET", "repos/OWNER/REPO/actions/secrets/public-key"), httpmock.StringResponse(testPubKeyJSON))
+	reg.Register(httpmock.REST("GET", "repos/OWNER/REPO/actions/secrets"), httpmock.StringResponse(`{"secrets":[]}`))
+	reg.Register(httpmock.REST("PUT", "repos/OWNER/REPO/actions/secrets/FOO"), httpmock.StatusStringResponse(204, ""))
+
+	io, _, _, _ := iostreams.Test()
+	factory := &cmdutil.Factory{
+		IOStreams: io,
+		HttpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: reg}, nil
+		},
+		BaseRepo: func() (ghrepo.Interface, error) {
+			return ghrepo.FromFullName("OWNER/REPO")
+		},
+	}
+
+	cmd := NewCmdCreate(factory, nil)
+	cmd.SetArgs([]string{"-f", f})
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	_, err := cmd.ExecuteC()
+	require.NoError(t, err)
+}
+
+func Test_runImport_orgSelectedResolvesRepoIDsOnce(t *testing.T) {
+	f := newEnvFile(t, "FOO=1\nBAR=2\nBAZ=3\n")
+	defer os.Remove(f)
+
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(httpmock.REST("GET", "orgs/my-org/actions/secrets/public-key"), httpmock.StringResponse(testPubKeyJSON))
+	reg.Register(httpmock.REST("GET", "orgs/my-org/actions/secrets"), httpmock.StringResponse(`{"secrets":[]}`))
+	reg.Register(httpmock.GraphQL(`query MapRepositoryNames`), httpmock.StringResponse(`{"data":{"myrepo":{"databaseId":1}}}`))
+	reg.Register(httpmock.REST("PUT", "orgs/my-org/actions/secrets/FOO"), httpmock.StatusStringResponse(204, ""))
+	reg.Register(httpmock.REST("PUT", "orgs/my-org/actions/secrets/BAR"), httpmock.StatusStringResponse(204, ""))
+	reg.Register(httpmock.REST("PUT", "orgs/my-org/actions/secrets/BAZ"), httpmock.StatusStringResponse(204, ""))
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+	baseRepo, err := ghrepo.FromFullName("OWNER/REPO")
+	require.NoError(t, err)
+
+	io, _, _, _ := iostreams.Test()
+	opts := &CreateOptions{
+		IO:              io,
+		OrgName:         "my-org",
+		Visibility:      visSelected,
+		Application:     Actions,
+		RepositoryNames: []string{"myrepo"},
+		EnvFile:         f,
+	}
+
+	err = runImport(client, baseRepo, opts)
+	require.NoError(t, err)
+
+	// reg.Verify asserts every registered stub, including the single
+	// MapRepositoryNames query, was actually called; if the lookup moved
+	// back inside the put closure it would register (and need) three.
+}
+
+func newEnvFile(t *testing.T, contents string) string {
+	t.Helper()
+	f, err := ioutil.TempFile("", "secrets-*.env")
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(contents)
+	require.NoError(t, err)
+	return f.Name()
+}
@@ -0,0 +1,94 @@
+package create
+
+import (
+	"github.com/cli/cli/api"
+)
+
+// diffSelectedRepos compares the currently selected repo IDs for an org
+// secret against wanted and returns the minimal sets to add and remove.
+func diffSelectedRepos(current, wanted []int) (toAdd, toRemove []int) {
+	currentSet := make(map[int]bool, len(current))
+	for _, id := range current {
+		currentSet[id] = true
+	}
+	wantedSet := make(map[int]bool, len(wanted))
+	for _, id := range wanted {
+		wantedSet[id] = true
+	}
+
+	for _, id := range wanted {
+		if !currentSet[id] {
+			toAdd = append(toAdd, id)
+		}
+	}
+	for _, id := range current {
+		if !wantedSet[id] {
+			toRemove = append(toRemove, id)
+		}
+	}
+
+	return toAdd, toRemove
+}
+
+// UpdateOrgSecretRepos resolves repositoryNames to IDs and either grants or
+// revokes their access to secretName, issuing only the calls needed to
+// change repositories not already in (or already out of) the selected list.
+func UpdateOrgSecretRepos(client *api.Client, host, orgName, secretName string, repositoryNames []string, revoke bool) error {
+	targetIDs, err := mapRepoNameToID(client, host, orgName, repositoryNames, Actions, 0)
+	if err != nil {
+		return err
+	}
+
+	current, err := listOrgSecretRepos(client, host, orgName, secretName)
+	if err != nil {
+		return err
+	}
+
+	var desired []int
+	if revoke {
+		desired = subtractRepoIDs(current, targetIDs.([]int))
+	} else {
+		desired = unionRepoIDs(current, targetIDs.([]int))
+	}
+
+	toAdd, toRemove := diffSelectedRepos(current, desired)
+
+	for _, id := range toAdd {
+		if err := putOrgSecretRepo(client, host, orgName, secretName, id); err != nil {
+			return err
+		}
+	}
+	for _, id := range toRemove {
+		if err := deleteOrgSecretRepo(client, host, orgName, secretName, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func unionRepoIDs(a, b []int) []int {
+	seen := make(map[int]bool, len(a)+len(b))
+	result := make([]int, 0, len(a)+len(b))
+	for _, id := range append(append([]int{}, a...), b...) {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func subtractRepoIDs(a, remove []int) []int {
+	removeSet := make(map[int]bool, len(remove))
+	for _, id := range remove {
+		removeSet[id] = true
+	}
+	result := make([]int, 0, len(a))
+	for _, id := range a {
+		if !removeSet[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
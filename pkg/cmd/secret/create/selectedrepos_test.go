@@ -0,0 +1,60 @@
+package create
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diffSelectedRepos(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  []int
+		wanted   []int
+		toAdd    []int
+		toRemove []int
+	}{
+		{
+			name:    "no change",
+			current: []int{1, 2, 3},
+			wanted:  []int{1, 2, 3},
+		},
+		{
+			name:    "add only",
+			current: []int{1},
+			wanted:  []int{1, 2, 3},
+			toAdd:   []int{2, 3},
+		},
+		{
+			name:     "remove only",
+			current:  []int{1, 2, 3},
+			wanted:   []int{1},
+			toRemove: []int{2, 3},
+		},
+		{
+			name:     "add and remove",
+			current:  []int{1, 2},
+			wanted:   []int{2, 3},
+			toAdd:    []int{3},
+			toRemove: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toAdd, toRemove := diffSelectedRepos(tt.current, tt.wanted)
+			assert.ElementsMatch(t, tt.toAdd, toAdd)
+			assert.ElementsMatch(t, tt.toRemove, toRemove)
+		})
+	}
+}
+
+func Test_unionRepoIDs(t *testing.T) {
+	assert.ElementsMatch(t, []int{1, 2, 3}, unionRepoIDs([]int{1, 2}, []int{2, 3}))
+	assert.ElementsMatch(t, []int{1, 2}, unionRepoIDs([]int{1, 2}, nil))
+}
+
+func Test_subtractRepoIDs(t *testing.T) {
+	assert.ElementsMatch(t, []int{1}, subtractRepoIDs([]int{1, 2, 3}, []int{2, 3}))
+	assert.ElementsMatch(t, []int{1, 2, 3}, subtractRepoIDs([]int{1, 2, 3}, nil))
+}
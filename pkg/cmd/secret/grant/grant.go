@@ -0,0 +1,90 @@
+package grant
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/config"
+	"github.com/cli/cli/pkg/cmd/secret/create"
+	"github.com/cli/cli/pkg/cmdutil"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type GrantOptions struct {
+	HttpClient func() (*http.Client, error)
+	IO         *iostreams.IOStreams
+	Config     func() (config.Config, error)
+
+	OrgName         string
+	SecretName      string
+	RepositoryNames []string
+	Revoke          bool
+}
+
+func NewCmdGrant(f *cmdutil.Factory, runF func(*GrantOptions) error) *cobra.Command {
+	return newCmdGrantRevoke(f, runF, false)
+}
+
+func NewCmdRevoke(f *cmdutil.Factory, runF func(*GrantOptions) error) *cobra.Command {
+	return newCmdGrantRevoke(f, runF, true)
+}
+
+func newCmdGrantRevoke(f *cmdutil.Factory, runF func(*GrantOptions) error, revoke bool) *cobra.Command {
+	opts := &GrantOptions{
+		IO:         f.IOStreams,
+		HttpClient: f.HttpClient,
+		Config:     f.Config,
+		Revoke:     revoke,
+	}
+
+	use, short := "grant <secret-name> <repo>...", "Grant access to an organization secret for one or more repositories"
+	if revoke {
+		use, short = "revoke <secret-name> <repo>...", "Revoke access to an organization secret for one or more repositories"
+	}
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.SecretName = args[0]
+			opts.RepositoryNames = args[1:]
+
+			if opts.OrgName == "" {
+				return cmdutil.FlagErrorf("`--org` is required")
+			}
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return runGrantRevoke(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.OrgName, "org", "o", "", "The organization the secret belongs to")
+
+	return cmd
+}
+
+func runGrantRevoke(opts *GrantOptions) error {
+	httpClient, err := opts.HttpClient()
+	if err != nil {
+		return fmt.Errorf("could not create http client: %w", err)
+	}
+	client := api.NewClientFromHTTP(httpClient)
+
+	cfg, err := opts.Config()
+	if err != nil {
+		return fmt.Errorf("could not read config: %w", err)
+	}
+	host, err := cfg.DefaultHost()
+	if err != nil {
+		return fmt.Errorf("could not determine host: %w", err)
+	}
+
+	// selected-repository grant/revoke is an Actions-secrets-only endpoint;
+	// GitHub doesn't yet expose the equivalent for Dependabot or Codespaces.
+	return create.UpdateOrgSecretRepos(client, host, opts.OrgName, opts.SecretName, opts.RepositoryNames, opts.Revoke)
+}
@@ -0,0 +1,136 @@
+package grant
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/cli/cli/internal/config"
+	"github.com/cli/cli/pkg/cmdutil"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewCmdGrant_requiresOrg(t *testing.T) {
+	io, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{IOStreams: io}
+
+	cmd := NewCmdGrant(f, func(opts *GrantOptions) error { return nil })
+	cmd.SetArgs([]string{"FOO", "OWNER/REPO"})
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	_, err := cmd.ExecuteC()
+	require.Error(t, err)
+	assert.Equal(t, "`--org` is required", err.Error())
+}
+
+func Test_NewCmdRevoke_requiresOrg(t *testing.T) {
+	io, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{IOStreams: io}
+
+	cmd := NewCmdRevoke(f, func(opts *GrantOptions) error { return nil })
+	cmd.SetArgs([]string{"FOO", "OWNER/REPO"})
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	_, err := cmd.ExecuteC()
+	require.Error(t, err)
+	assert.Equal(t, "`--org` is required", err.Error())
+}
+
+func Test_runGrantRevoke_grant(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(
+		httpmock.GraphQL(`query MapRepositoryNames`),
+		httpmock.StringResponse(`{"data":{"repo_c":{"databaseId":3}}}`),
+	)
+	reg.Register(
+		httpmock.REST("GET", "orgs/my-org/actions/secrets/FOO/repositories"),
+		httpmock.StringResponse(`{"repositories":[{"id":1},{"id":2}]}`),
+	)
+	reg.Register(
+		httpmock.REST("PUT", "orgs/my-org/actions/secrets/FOO/repositories/3"),
+		httpmock.StatusStringResponse(204, ""),
+	)
+
+	io, _, _, _ := iostreams.Test()
+	opts := &GrantOptions{
+		IO: io,
+		HttpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: reg}, nil
+		},
+		Config: func() (config.Config, error) {
+			return config.NewBlankConfig(), nil
+		},
+		OrgName:         "my-org",
+		SecretName:      "FOO",
+		RepositoryNames: []string{"repo_c"},
+	}
+
+	err := runGrantRevoke(opts)
+	require.NoError(t, err)
+}
+
+func Test_runGrantRevoke_revoke(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	reg.Register(
+		httpmock.GraphQL(`query MapRepositoryNames`),
+		httpmock.StringResponse(`{"data":{"repo_c":{"databaseId":3}}}`),
+	)
+	reg.Register(
+		httpmock.REST("GET", "orgs/my-org/actions/secrets/FOO/repositories"),
+		httpmock.StringResponse(`{"repositories":[{"id":1},{"id":2},{"id":3}]}`),
+	)
+	reg.Register(
+		httpmock.REST("DELETE", "orgs/my-org/actions/secrets/FOO/repositories/3"),
+		httpmock.StatusStringResponse(204, ""),
+	)
+
+	io, _, _, _ := iostreams.Test()
+	opts := &GrantOptions{
+		IO: io,
+		HttpClient: func() (*http.Client, error) {
+			return &http.Client{Transport: reg}, nil
+		},
+		Config: func() (config.Config, error) {
+			return config.NewBlankConfig(), nil
+		},
+		OrgName:         "my-org",
+		SecretName:      "FOO",
+		RepositoryNames: []string{"repo_c"},
+		Revoke:          true,
+	}
+
+	err := runGrantRevoke(opts)
+	require.NoError(t, err)
+}
+
+func Test_NewCmdGrant_passesArgsAndFlagsThroughRunF(t *testing.T) {
+	io, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{IOStreams: io}
+
+	var gotOpts *GrantOptions
+	cmd := NewCmdGrant(f, func(opts *GrantOptions) error {
+		gotOpts = opts
+		return nil
+	})
+	cmd.SetArgs([]string{"FOO", "repo-a", "repo-b", "--org", "my-org"})
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	_, err := cmd.ExecuteC()
+	require.NoError(t, err)
+	require.NotNil(t, gotOpts)
+	assert.Equal(t, "FOO", gotOpts.SecretName)
+	assert.Equal(t, []string{"repo-a", "repo-b"}, gotOpts.RepositoryNames)
+	assert.Equal(t, "my-org", gotOpts.OrgName)
+	assert.False(t, gotOpts.Revoke)
+}